@@ -0,0 +1,288 @@
+package run
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/go-cmd/cmd"
+)
+
+// ConcurrencyErrorMatcher reports whether status indicates a command failed
+// because of contention with other concurrently running commands, rather
+// than a real, reproducible failure.
+type ConcurrencyErrorMatcher func(status cmd.Status) bool
+
+// DefaultConcurrencyErrorMatcher matches a handful of common
+// concurrency-class failures: the process hitting "resource temporarily
+// unavailable" (EAGAIN), "Text file busy" (a binary being written while
+// exec'd), and the sqlite-style "database is locked" message.
+func DefaultConcurrencyErrorMatcher(status cmd.Status) bool {
+	for _, line := range status.Stderr {
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "resource temporarily unavailable") ||
+			strings.Contains(lower, "text file busy") ||
+			strings.Contains(lower, "database is locked") ||
+			strings.Contains(lower, "database-locked") {
+			return true
+		}
+	}
+	return false
+}
+
+// RunAdaptive is a Runner that runs commands in parallel like RunParallel,
+// but when a command fails with a concurrency-class error (as reported by
+// its ConcurrencyErrorMatcher), it re-runs that command serially instead of
+// giving up. This is the same trick golang.org/x/tools/internal/gocommand
+// uses to work around tools that don't tolerate concurrent invocation.
+type RunAdaptive struct {
+	maxInFlight int
+	maxRetries  int
+	matcher     ConcurrencyErrorMatcher
+	// --
+	*sync.Mutex
+	eventBus
+	running  bool
+	cmd      map[int]*cmd.Cmd
+	status   []cmd.Status
+	attempts [][]cmd.Status
+	stopChan chan struct{}
+}
+
+// NewRunAdaptive creates a new RunAdaptive. maxInFlight bounds normal
+// parallel execution; maxRetries bounds how many times a command may be
+// re-run serially after a concurrency-class error. If matcher is nil,
+// DefaultConcurrencyErrorMatcher is used.
+func NewRunAdaptive(maxInFlight, maxRetries int, matcher ConcurrencyErrorMatcher) *RunAdaptive {
+	if matcher == nil {
+		matcher = DefaultConcurrencyErrorMatcher
+	}
+	return &RunAdaptive{
+		maxInFlight: maxInFlight,
+		maxRetries:  maxRetries,
+		matcher:     matcher,
+		// --
+		Mutex: &sync.Mutex{},
+	}
+}
+
+// Attempts returns every attempt's Status for each command, in cmds order.
+// Attempts[i] has one entry unless cmds[i] was re-run serially, in which
+// case it has one entry per attempt, oldest first.
+func (r *RunAdaptive) Attempts() [][]cmd.Status {
+	r.Lock()
+	defer r.Unlock()
+	return r.attempts
+}
+
+// Run runs cmds, at most maxInFlight at a time. A command whose Status
+// matches the ConcurrencyErrorMatcher is re-enqueued and re-run serially,
+// up to maxRetries times, instead of being treated as a failure.
+func (r *RunAdaptive) Run(ctx context.Context, cmds []cmd.Cmd) ([]cmd.Status, error) {
+	r.Lock()
+	if r.running {
+		r.Unlock()
+		return nil, ErrRunning
+	}
+
+	r.status = make([]cmd.Status, len(cmds))
+	r.attempts = make([][]cmd.Status, len(cmds))
+	r.cmd = map[int]*cmd.Cmd{}
+	r.stopChan = make(chan struct{})
+	r.running = true
+	r.Unlock()
+
+	defer func() {
+		r.Lock()
+		r.running = false
+		r.Unlock()
+	}()
+
+	// inFlight gates normal parallel work; serialized gates the one command
+	// allowed to run alone after hitting a concurrency error. A retrying
+	// command acquires serialized while still holding its inFlight slot, so
+	// normal parallel work and the serialized retry never deadlock waiting
+	// on each other.
+	inFlight := make(chan struct{}, r.maxInFlight)
+	serialized := make(chan struct{}, 1)
+
+	watchDone := make(chan struct{})
+	defer close(watchDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.Stop()
+		case <-watchDone:
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var failed bool
+	var failedMux sync.Mutex
+
+dispatch:
+	for i, c := range cmds {
+		if r.stopped() {
+			break dispatch
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		case <-r.stopChan:
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(i int, c cmd.Cmd) {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+
+			status, exhausted := r.runWithRetries(i, c, serialized)
+
+			r.Lock()
+			r.status[i] = status
+			r.Unlock()
+
+			// exhausted means every retry still looked like a concurrency
+			// error; a non-matching non-zero exit is a real failure too.
+			if exhausted || (status.Exit != 0 && !r.matcher(status)) {
+				failedMux.Lock()
+				failed = true
+				failedMux.Unlock()
+			}
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	r.Lock()
+	status := r.status
+	r.Unlock()
+
+	if ctx.Err() != nil {
+		return status, ctx.Err()
+	}
+	if failed {
+		return status, ErrNonzeroExit
+	}
+	if r.stopped() {
+		return status, ErrStopped
+	}
+	return status, nil
+}
+
+// runWithRetries runs c under a parallel slot, and if its Status matches the
+// ConcurrencyErrorMatcher, re-runs it serially up to maxRetries times. It
+// returns the final Status and whether retries were exhausted while the
+// matcher still matched.
+func (r *RunAdaptive) runWithRetries(i int, c cmd.Cmd, serialized chan struct{}) (cmd.Status, bool) {
+	status := r.runOne(i, c)
+	r.recordAttempt(i, status)
+
+	if !r.matcher(status) {
+		return status, false
+	}
+
+	for attempt := 0; attempt < r.maxRetries; attempt++ {
+		if r.stopped() {
+			return status, false
+		}
+		serialized <- struct{}{}
+		status = r.runOne(i, c)
+		<-serialized
+		r.recordAttempt(i, status)
+
+		if !r.matcher(status) {
+			return status, false
+		}
+	}
+
+	return status, true
+}
+
+func (r *RunAdaptive) runOne(i int, c cmd.Cmd) cmd.Status {
+	child := cmd.NewCmd(c.Name, c.Args...)
+	r.Lock()
+	r.cmd[i] = child
+	r.Unlock()
+	r.publish(Event{Index: i, Cmd: c, Kind: Started})
+
+	startChan := child.Start()
+	var status cmd.Status
+	var stopped bool
+	select {
+	case status = <-startChan:
+	case <-r.stopChan:
+		child.Stop()
+		status = <-startChan
+		stopped = true
+	}
+
+	r.Lock()
+	delete(r.cmd, i)
+	r.Unlock()
+
+	if stopped {
+		r.publish(Event{Index: i, Cmd: c, Kind: Stopped, Status: status})
+		return status
+	}
+	r.publish(Event{Index: i, Cmd: c, Kind: Finished, Status: status})
+
+	return status
+}
+
+func (r *RunAdaptive) recordAttempt(i int, status cmd.Status) {
+	r.Lock()
+	defer r.Unlock()
+	r.attempts[i] = append(r.attempts[i], status)
+}
+
+// Stop stops Run if Run is still running. It closes the internal stop
+// channel, which prevents any more commands from being dispatched or
+// retried, then calls Stop on every currently running Cmd. Stop is
+// idempotent.
+func (r *RunAdaptive) Stop() error {
+	r.Lock()
+
+	if !r.running {
+		r.Unlock()
+		return nil
+	}
+
+	if !r.stopped() {
+		close(r.stopChan)
+	}
+
+	running := make([]*cmd.Cmd, 0, len(r.cmd))
+	for _, c := range r.cmd {
+		running = append(running, c)
+	}
+	r.Unlock()
+
+	var err error
+	for _, c := range running {
+		if e := c.Stop(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Status returns a live snapshot of every command's Status, in cmds order.
+func (r *RunAdaptive) Status() []cmd.Status {
+	r.Lock()
+	defer r.Unlock()
+	for i, c := range r.cmd {
+		r.status[i] = c.Status()
+	}
+	return r.status
+}
+
+func (r *RunAdaptive) stopped() bool {
+	select {
+	case <-r.stopChan:
+		return true
+	default:
+		return false
+	}
+}
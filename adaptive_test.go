@@ -0,0 +1,104 @@
+package run_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-cmd/cmd"
+	"github.com/go-cmd/run"
+)
+
+func TestRunAdaptiveOK(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "echo", Args: []string{"hello"}},
+		{Name: "echo", Args: []string{"world"}},
+	}
+
+	r := run.NewRunAdaptive(2, 3, nil)
+	status, err := r.Run(context.Background(), cmds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 2 {
+		t.Fatalf("expected 2 Status, got %d", len(status))
+	}
+	for _, attempts := range r.Attempts() {
+		if len(attempts) != 1 {
+			t.Errorf("expected 1 attempt for a clean run, got %d", len(attempts))
+		}
+	}
+}
+
+func TestRunAdaptiveNonConcurrencyFailure(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "false", Args: []string{}},
+	}
+
+	r := run.NewRunAdaptive(1, 3, nil)
+	_, err := r.Run(context.Background(), cmds)
+	if err != run.ErrNonzeroExit {
+		t.Errorf("got err %v, expected ErrNonzeroExit", err)
+	}
+	if len(r.Attempts()[0]) != 1 {
+		t.Errorf("expected 1 attempt, a non-matching failure isn't retried, got %d", len(r.Attempts()[0]))
+	}
+}
+
+func TestRunAdaptiveSerializesOnConcurrencyError(t *testing.T) {
+	// Fails with a matched concurrency error on its first run, then
+	// succeeds, so this drives the serialize-and-retry path in
+	// runWithRetries rather than the matcher alone.
+	marker := filepath.Join(t.TempDir(), "ran")
+	script := fmt.Sprintf(`if [ -f %s ]; then exit 0; fi; touch %s; echo "resource temporarily unavailable" >&2; exit 1`, marker, marker)
+	cmds := []cmd.Cmd{
+		{Name: "sh", Args: []string{"-c", script}},
+	}
+
+	r := run.NewRunAdaptive(2, 3, nil)
+	status, err := r.Run(context.Background(), cmds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status[0].Exit != 0 {
+		t.Errorf("got exit %d, expected 0 after the serialized retry", status[0].Exit)
+	}
+	if len(r.Attempts()[0]) != 2 {
+		t.Errorf("expected 2 attempts (concurrency error, then success), got %d", len(r.Attempts()[0]))
+	}
+}
+
+func TestRunAdaptiveExhaustsRetriesOnConcurrencyError(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "sh", Args: []string{"-c", `echo "resource temporarily unavailable" >&2; exit 1`}},
+	}
+
+	r := run.NewRunAdaptive(1, 2, nil)
+	_, err := r.Run(context.Background(), cmds)
+	if err != run.ErrNonzeroExit {
+		t.Errorf("got err %v, expected ErrNonzeroExit", err)
+	}
+	if len(r.Attempts()[0]) != 3 { // 1 initial attempt + 2 retries, all matching
+		t.Errorf("expected 3 attempts, got %d", len(r.Attempts()[0]))
+	}
+}
+
+func TestDefaultConcurrencyErrorMatcher(t *testing.T) {
+	cases := []struct {
+		stderr []string
+		want   bool
+	}{
+		{[]string{"resource temporarily unavailable"}, true},
+		{[]string{"Text file busy"}, true},
+		{[]string{"database is locked"}, true},
+		{[]string{"no such file or directory"}, false},
+		{nil, false},
+	}
+	for _, c := range cases {
+		got := run.DefaultConcurrencyErrorMatcher(cmd.Status{Stderr: c.stderr})
+		if got != c.want {
+			t.Errorf("matcher(%v) = %v, expected %v", c.stderr, got, c.want)
+		}
+	}
+}
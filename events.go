@@ -0,0 +1,123 @@
+package run
+
+import (
+	"sync"
+
+	"github.com/go-cmd/cmd"
+)
+
+// EventKind identifies the lifecycle transition an Event represents.
+type EventKind int
+
+const (
+	// Started is sent when a Cmd begins running.
+	Started EventKind = iota
+	// Finished is sent when a Cmd completes normally, regardless of exit code.
+	Finished
+	// Stopped is sent when a Cmd is stopped before it completed on its own.
+	Stopped
+	// Failed is sent when a Cmd could not be run at all.
+	Failed
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case Started:
+		return "Started"
+	case Finished:
+		return "Finished"
+	case Stopped:
+		return "Stopped"
+	case Failed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes a lifecycle transition of a single Cmd within a Runner.
+// Index is the Cmd's position in the slice passed to Run.
+type Event struct {
+	Index  int
+	Cmd    cmd.Cmd
+	Kind   EventKind
+	Status cmd.Status
+}
+
+// eventBus fans events out to subscribers. It's embedded by Runner
+// implementations rather than duplicated in each one.
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// Subscribe returns a channel that receives every Event published by the
+// Runner until Unsubscribe is called. Sends are non-blocking: a subscriber
+// that isn't keeping up misses events rather than stalling the Runner.
+func (b *eventBus) Subscribe() <-chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = map[chan Event]struct{}{}
+	}
+	ch := make(chan Event, 16)
+	b.subs[ch] = struct{}{}
+	return ch
+}
+
+// Unsubscribe stops delivering events to a channel returned by Subscribe and
+// closes it. Unsubscribe is idempotent.
+func (b *eventBus) Unsubscribe(ch <-chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		if sub == ch {
+			delete(b.subs, sub)
+			close(sub)
+			return
+		}
+	}
+}
+
+// publish fans e out to every current subscriber without blocking.
+func (b *eventBus) publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub <- e:
+		default:
+		}
+	}
+}
+
+// WaitFor blocks until the command at index finishes, is stopped, or fails
+// to run, then returns its final Status. It's built on Subscribe/Unsubscribe
+// so callers don't have to poll Status in a loop.
+//
+// Subscribe only delivers events published after it's called, so WaitFor
+// subscribes first, then checks Status for a command that already reached a
+// terminal state before the subscription existed. Without that check,
+// WaitFor on an index that finished before WaitFor was called would wait
+// forever for an event that already came and went.
+func WaitFor(r Runner, index int) cmd.Status {
+	ch := r.Subscribe()
+	defer r.Unsubscribe(ch)
+
+	if status := r.Status(); index < len(status) {
+		if s := status[index]; s.Complete || s.Error != nil {
+			return s
+		}
+	}
+
+	for e := range ch {
+		if e.Index != index {
+			continue
+		}
+		switch e.Kind {
+		case Finished, Stopped, Failed:
+			return e.Status
+		}
+	}
+	return cmd.Status{}
+}
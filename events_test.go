@@ -0,0 +1,70 @@
+package run_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-cmd/cmd"
+	"github.com/go-cmd/run"
+)
+
+func TestRunSyncSubscribe(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "echo", Args: []string{"hello"}},
+		{Name: "echo", Args: []string{"world"}},
+	}
+
+	r := run.NewRunSync(true)
+	events := r.Subscribe()
+	defer r.Unsubscribe(events)
+
+	doneChan := make(chan struct{})
+	go func() {
+		r.Run(context.Background(), cmds)
+		close(doneChan)
+	}()
+
+	var kinds []run.EventKind
+	for len(kinds) < 4 {
+		select {
+		case e := <-events:
+			kinds = append(kinds, e.Kind)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for events")
+		}
+	}
+	<-doneChan
+
+	want := []run.EventKind{run.Started, run.Finished, run.Started, run.Finished}
+	for i, k := range want {
+		if kinds[i] != k {
+			t.Errorf("event[%d] = %s, expected %s", i, kinds[i], k)
+		}
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "echo", Args: []string{"hello"}},
+		{Name: "echo", Args: []string{"world"}},
+	}
+
+	r := run.NewRunSync(true)
+
+	doneChan := make(chan struct{})
+	go func() {
+		r.Run(context.Background(), cmds)
+		close(doneChan)
+	}()
+
+	// Give both commands time to finish before WaitFor subscribes, so this
+	// exercises the already-finished case rather than relying on scheduling
+	// luck to have Subscribe win the race against the command completing.
+	<-doneChan
+
+	status := run.WaitFor(r, 1)
+	if status.Exit != 0 {
+		t.Errorf("got exit %d, expected 0", status.Exit)
+	}
+}
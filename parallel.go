@@ -0,0 +1,209 @@
+package run
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-cmd/cmd"
+)
+
+// RunParallel is a Runner that runs commands concurrently with a bounded
+// number of workers, along the lines of the Runner in
+// golang.org/x/tools/internal/gocommand. It's useful when commands are
+// independent of one another and the caller wants them to run as fast as
+// possible without overwhelming the host.
+type RunParallel struct {
+	maxInFlight int
+	stopOnError bool
+	// --
+	*sync.Mutex
+	eventBus
+	running  bool
+	cmd      map[int]*cmd.Cmd // currently running, keyed by index
+	status   []cmd.Status
+	stopChan chan struct{}
+}
+
+// NewRunParallel creates a new RunParallel that runs at most maxInFlight
+// commands at once. If stopOnError is true, the first command to exit
+// non-zero stops the runner and Run returns ErrNonzeroExit once the
+// in-flight commands have drained.
+func NewRunParallel(maxInFlight int, stopOnError bool) *RunParallel {
+	return &RunParallel{
+		maxInFlight: maxInFlight,
+		stopOnError: stopOnError,
+		// --
+		Mutex: &sync.Mutex{},
+	}
+}
+
+// Run runs the list of Cmd, at most maxInFlight at a time, and waits for them
+// to complete. It returns Status for each Cmd in the same order as cmds. The
+// returned Status always has the same length as cmds, but if a Cmd is not ran
+// its Status value is the zero value. Returned Status and error are not
+// mutually exclusive: Status for Cmd that ran are always returned, even if an
+// error is also returned.
+//
+// If ctx is canceled or its deadline expires, Run stops all in-flight
+// commands and returns ctx.Err() once they've drained.
+func (r *RunParallel) Run(ctx context.Context, cmds []cmd.Cmd) ([]cmd.Status, error) {
+	r.Lock()
+	if r.running {
+		r.Unlock()
+		return nil, ErrRunning
+	}
+
+	r.status = make([]cmd.Status, len(cmds))
+	r.cmd = map[int]*cmd.Cmd{}
+	r.stopChan = make(chan struct{})
+	r.running = true
+	r.Unlock()
+
+	defer func() {
+		r.Lock()
+		r.running = false
+		r.Unlock()
+	}()
+
+	inFlight := make(chan struct{}, r.maxInFlight)
+
+	var wg sync.WaitGroup
+	var nonzero bool
+	var nonzeroMux sync.Mutex
+
+	watchDone := make(chan struct{})
+	defer close(watchDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.Stop()
+		case <-watchDone:
+		}
+	}()
+
+dispatch:
+	for i, c := range cmds {
+		if r.stopped() {
+			break dispatch
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		case <-r.stopChan:
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(i int, c cmd.Cmd) {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+
+			if r.stopped() {
+				return
+			}
+
+			child := cmd.NewCmd(c.Name, c.Args...)
+			r.Lock()
+			r.cmd[i] = child
+			r.Unlock()
+			r.publish(Event{Index: i, Cmd: c, Kind: Started})
+
+			startChan := child.Start()
+			var status cmd.Status
+			var stopped bool
+			select {
+			case status = <-startChan:
+			case <-r.stopChan:
+				child.Stop()
+				status = <-startChan
+				stopped = true
+			}
+
+			r.Lock()
+			r.status[i] = status
+			delete(r.cmd, i)
+			r.Unlock()
+
+			if stopped {
+				r.publish(Event{Index: i, Cmd: c, Kind: Stopped, Status: status})
+				return
+			}
+			r.publish(Event{Index: i, Cmd: c, Kind: Finished, Status: status})
+
+			if r.stopOnError && status.Exit != 0 {
+				nonzeroMux.Lock()
+				nonzero = true
+				nonzeroMux.Unlock()
+				r.Stop()
+			}
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	r.Lock()
+	status := r.status
+	r.Unlock()
+
+	if ctx.Err() != nil {
+		return status, ctx.Err()
+	}
+	if nonzero {
+		return status, ErrNonzeroExit
+	}
+	if r.stopped() {
+		return status, ErrStopped
+	}
+	return status, nil
+}
+
+// Stop stops Run if Run is still running. It closes the internal stop
+// channel, which prevents any more commands from being dispatched, then
+// calls Stop on every currently running Cmd. Stop is idempotent.
+func (r *RunParallel) Stop() error {
+	r.Lock()
+
+	if !r.running {
+		r.Unlock()
+		return nil
+	}
+
+	if !r.stopped() {
+		close(r.stopChan)
+	}
+
+	running := make([]*cmd.Cmd, 0, len(r.cmd))
+	for _, c := range r.cmd {
+		running = append(running, c)
+	}
+	r.Unlock()
+
+	var err error
+	for _, c := range running {
+		if e := c.Stop(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Status returns a live snapshot of every Cmd's Status, in the same order as
+// the cmds passed to Run. Commands that are still running are queried
+// directly; commands that have finished return their final Status.
+func (r *RunParallel) Status() []cmd.Status {
+	r.Lock()
+	defer r.Unlock()
+	for i, c := range r.cmd {
+		r.status[i] = c.Status()
+	}
+	return r.status
+}
+
+func (r *RunParallel) stopped() bool {
+	select {
+	case <-r.stopChan:
+		return true
+	default:
+		return false
+	}
+}
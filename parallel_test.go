@@ -0,0 +1,94 @@
+package run_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-cmd/cmd"
+	"github.com/go-cmd/run"
+)
+
+func TestRunParallelOK(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "echo", Args: []string{"hello"}},
+		{Name: "echo", Args: []string{"world"}},
+		{Name: "echo", Args: []string{"again"}},
+	}
+
+	r := run.NewRunParallel(2, true)
+	status, err := r.Run(context.Background(), cmds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 3 {
+		t.Fatalf("expected 3 Status, got %d", len(status))
+	}
+	for i, s := range status {
+		if s.Exit != 0 {
+			t.Errorf("status[%d].Exit = %d, expected 0", i, s.Exit)
+		}
+	}
+}
+
+func TestRunParallelStopOnError(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "false", Args: []string{}},
+		{Name: "sleep", Args: []string{"5"}},
+	}
+
+	r := run.NewRunParallel(2, true)
+	_, err := r.Run(context.Background(), cmds)
+	if err != run.ErrNonzeroExit {
+		t.Errorf("got err %v, expected ErrNonzeroExit", err)
+	}
+}
+
+func TestRunParallelStop(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "./test/count-and-sleep", Args: []string{"5", "5"}},
+		{Name: "./test/count-and-sleep", Args: []string{"5", "5"}},
+	}
+
+	r := run.NewRunParallel(2, false)
+
+	doneChan := make(chan struct{})
+	go func() {
+		r.Run(context.Background(), cmds)
+		close(doneChan)
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	if err := r.Stop(); err != nil {
+		t.Error(err)
+	}
+
+	select {
+	case <-doneChan:
+	case <-time.After(1 * time.Second):
+		t.Fatal("timeout waiting for Run to return")
+	}
+}
+
+func TestRunParallelRunning(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "sleep", Args: []string{"1"}},
+	}
+
+	r := run.NewRunParallel(1, false)
+
+	doneChan := make(chan struct{})
+	go func() {
+		r.Run(context.Background(), cmds)
+		close(doneChan)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+
+	if _, err := r.Run(context.Background(), cmds); err != run.ErrRunning {
+		t.Errorf("got err %v, expected ErrRunning", err)
+	}
+
+	<-doneChan
+}
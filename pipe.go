@@ -0,0 +1,279 @@
+package run
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/go-cmd/cmd"
+)
+
+// RunPipe is a Runner that executes its commands as a Unix-style pipeline:
+// cmds[i]'s stdout feeds cmds[i+1]'s stdin, and only the final command's
+// stdout is captured into its Status.Stdout.
+//
+// Every stage but the last runs with cmd.Options.Streaming so RunPipe can
+// relay its output, line by line, into the next stage's stdin via
+// cmd.Cmd.StartWithStdin. The last stage runs buffered, same as every other
+// Runner in this package, so its Status.Stdout/Stderr are captured normally.
+type RunPipe struct {
+	// --
+	*sync.Mutex
+	eventBus
+	running  bool
+	cmd      []*cmd.Cmd       // one per stage, in pipeline order
+	writers  []*io.PipeWriter // stage i's relay pipe into stage i+1's stdin, nil for the last stage
+	status   []cmd.Status
+	stopChan chan struct{}
+}
+
+// NewRunPipe creates a new RunPipe.
+func NewRunPipe() *RunPipe {
+	return &RunPipe{
+		Mutex: &sync.Mutex{},
+	}
+}
+
+// Run wires cmds into a pipeline and waits for every stage to complete. It
+// returns one Status per stage, in pipeline order. Only the last stage's
+// Status.Stdout is populated; earlier stages' stdout is consumed by the next
+// stage instead of being captured. Run returns nil, nil for an empty cmds.
+//
+// If ctx is canceled or its deadline expires, Run stops the pipeline and
+// returns ctx.Err().
+func (r *RunPipe) Run(ctx context.Context, cmds []cmd.Cmd) ([]cmd.Status, error) {
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+
+	r.Lock()
+	if r.running {
+		r.Unlock()
+		return nil, ErrRunning
+	}
+
+	r.status = make([]cmd.Status, len(cmds))
+	r.cmd = make([]*cmd.Cmd, len(cmds))
+	r.writers = make([]*io.PipeWriter, len(cmds))
+	r.stopChan = make(chan struct{})
+	r.running = true
+	r.Unlock()
+
+	defer func() {
+		r.Lock()
+		r.running = false
+		r.Unlock()
+	}()
+
+	watchDone := make(chan struct{})
+	defer close(watchDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.Stop()
+		case <-watchDone:
+		}
+	}()
+
+	// Start every stage up front: they all run concurrently, each stage's
+	// relay goroutine feeding the next stage's stdin as output arrives. If
+	// the pipeline is stopped while stages are still being started, stop
+	// dispatching; stages never started keep their zero Status, same as
+	// RunParallel/RunAdaptive treat commands they never got to.
+	statusChans := make([]<-chan cmd.Status, len(cmds))
+	var stdin io.Reader
+	started := 0
+	for i, c := range cmds {
+		if r.stopped() {
+			break
+		}
+
+		last := i == len(cmds)-1
+		child := cmd.NewCmdOptions(cmd.Options{Buffered: last, Streaming: !last}, c.Name, c.Args...)
+
+		r.Lock()
+		r.cmd[i] = child
+		r.Unlock()
+
+		var nextStdin io.Reader
+		if !last {
+			pr, pw := io.Pipe()
+			r.Lock()
+			r.writers[i] = pw
+			r.Unlock()
+			go relayLines(child.Stdout, pw)
+			nextStdin = pr
+		}
+
+		statusChans[i] = child.StartWithStdin(stdin)
+		r.publish(Event{Index: i, Cmd: c, Kind: Started})
+		stdin = nextStdin
+		started = i + 1
+	}
+	if started > 0 && started < len(cmds) {
+		// The last stage we did start expected a downstream reader that will
+		// never arrive now; close its relay pipe so it can't block forever
+		// writing into it.
+		r.Lock()
+		if w := r.writers[started-1]; w != nil {
+			w.Close()
+		}
+		r.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(started)
+	for i := 0; i < started; i++ {
+		c := cmds[i]
+		go func(i int, c cmd.Cmd, last bool) {
+			defer wg.Done()
+
+			// A non-last stage's Stderr is only available as a stream, so
+			// collect it here and fold it into Status the same way Buffered
+			// does for the last stage.
+			var stderr []string
+			stderrDone := make(chan struct{})
+			if last {
+				close(stderrDone)
+			} else {
+				child := r.cmd[i]
+				go func() {
+					defer close(stderrDone)
+					for line := range child.Stderr {
+						stderr = append(stderr, line)
+					}
+				}()
+			}
+
+			// Race this stage's own completion against the pipeline being
+			// stopped, the same way RunSync/RunParallel/RunRetry do, so the
+			// published event reflects whether THIS stage was actually cut
+			// short rather than whatever r.stopped() happens to read after
+			// the fact.
+			var status cmd.Status
+			var stopped bool
+			select {
+			case status = <-statusChans[i]:
+			case <-r.stopChan:
+				r.cmd[i].Stop()
+				status = <-statusChans[i]
+				stopped = true
+			}
+			<-stderrDone
+			if !last {
+				status.Stderr = stderr
+			}
+
+			r.Lock()
+			r.status[i] = status
+			r.Unlock()
+
+			kind := Finished
+			switch {
+			case status.PID == 0 && status.Error != nil:
+				kind = Failed
+			case stopped:
+				kind = Stopped
+			}
+			r.publish(Event{Index: i, Cmd: c, Kind: kind, Status: status})
+		}(i, c, i == len(cmds)-1)
+	}
+	wg.Wait()
+
+	r.Lock()
+	status := append([]cmd.Status(nil), r.status...)
+	r.Unlock()
+
+	if r.stopped() {
+		return status, ErrStopped
+	}
+	if ctx.Err() != nil {
+		return status, ctx.Err()
+	}
+	for _, s := range status {
+		if s.Exit != 0 {
+			return status, ErrNonzeroExit
+		}
+	}
+	return status, nil
+}
+
+// relayLines copies lines from a streaming stage's Stdout onto the next
+// stage's stdin, reconstructing newlines, until lines is drained (the stage
+// finished) or closes w.
+//
+// Once a write fails (the reader went away, e.g. the next stage already
+// exited), relayLines keeps draining lines without forwarding instead of
+// returning immediately. cmd.Cmd's own Wait doesn't return until its
+// internal copy of the process's stdout has finished writing every line to
+// this channel; abandoning the channel once the pipe is gone would leave
+// that copy permanently blocked trying to send a line nobody's receiving,
+// which in turn would leave this stage's Status stuck, never delivered.
+func relayLines(lines <-chan string, w *io.PipeWriter) {
+	failed := false
+	for line := range lines {
+		if failed {
+			continue
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			failed = true
+		}
+	}
+	w.Close()
+}
+
+// Stop stops Run if Run is still running. It closes the internal stop
+// channel, which prevents any more Stopped/Finished bookkeeping from being
+// missed, then stops every stage and closes every relay pipe so a stage
+// blocked writing to a stage that already exited can't deadlock. Stop is
+// idempotent.
+func (r *RunPipe) Stop() error {
+	r.Lock()
+	if !r.running {
+		r.Unlock()
+		return nil
+	}
+	if !r.stopped() {
+		close(r.stopChan)
+	}
+	cmds := append([]*cmd.Cmd(nil), r.cmd...)
+	writers := append([]*io.PipeWriter(nil), r.writers...)
+	r.Unlock()
+
+	var err error
+	for i := len(cmds) - 1; i >= 0; i-- {
+		if cmds[i] == nil {
+			continue
+		}
+		if e := cmds[i].Stop(); e != nil {
+			err = e
+		}
+	}
+	for _, w := range writers {
+		if w != nil {
+			w.Close()
+		}
+	}
+	return err
+}
+
+// Status returns a live snapshot of every stage's Status, in pipeline order.
+func (r *RunPipe) Status() []cmd.Status {
+	r.Lock()
+	defer r.Unlock()
+	for i, c := range r.cmd {
+		if c != nil {
+			r.status[i] = c.Status()
+		}
+	}
+	return r.status
+}
+
+func (r *RunPipe) stopped() bool {
+	select {
+	case <-r.stopChan:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,80 @@
+package run_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-cmd/cmd"
+	"github.com/go-cmd/run"
+)
+
+func TestRunPipeOK(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "echo", Args: []string{"hello world"}},
+		{Name: "tr", Args: []string{"a-z", "A-Z"}},
+	}
+
+	r := run.NewRunPipe()
+	status, err := r.Run(context.Background(), cmds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 2 {
+		t.Fatalf("expected 2 Status, got %d", len(status))
+	}
+
+	got := strings.Join(status[1].Stdout, "\n")
+	if got != "HELLO WORLD" {
+		t.Errorf("got final stage stdout %q, expected %q", got, "HELLO WORLD")
+	}
+	if len(status[0].Stdout) != 0 {
+		t.Errorf("expected empty stdout for non-final stage, got %v", status[0].Stdout)
+	}
+}
+
+func TestRunPipeStop(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "yes", Args: []string{}},
+		{Name: "sleep", Args: []string{"5"}},
+	}
+
+	r := run.NewRunPipe()
+
+	var gotErr error
+	doneChan := make(chan struct{})
+	go func() {
+		_, gotErr = r.Run(context.Background(), cmds)
+		close(doneChan)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+
+	if err := r.Stop(); err != nil {
+		t.Error(err)
+	}
+
+	select {
+	case <-doneChan:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for Run to return")
+	}
+
+	if gotErr != run.ErrStopped {
+		t.Errorf("got err %v, expected ErrStopped", gotErr)
+	}
+}
+
+func TestRunPipeNonzeroExit(t *testing.T) {
+	cmds := []cmd.Cmd{
+		{Name: "false", Args: []string{}},
+		{Name: "cat", Args: []string{}},
+	}
+
+	r := run.NewRunPipe()
+	_, err := r.Run(context.Background(), cmds)
+	if err != run.ErrNonzeroExit {
+		t.Errorf("got err %v, expected ErrNonzeroExit", err)
+	}
+}
@@ -0,0 +1,228 @@
+package run
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-cmd/cmd"
+)
+
+// RetryCmd wraps a cmd.Cmd with a per-command retry policy. RunRetry uses
+// Timeout to bound a single attempt, Retries to cap the number of retries
+// after the first attempt fails or times out, and Backoff as the delay
+// between attempts. A zero Timeout means no per-attempt timeout.
+type RetryCmd struct {
+	Cmd     cmd.Cmd
+	Timeout time.Duration
+	Retries int
+	Backoff time.Duration
+}
+
+// RunRetry is a Runner that runs commands synchronously in the order given,
+// retrying a command that times out or exits non-zero up to its Retries
+// limit, waiting Backoff between attempts. It's meant for flaky commands
+// where RunSync's run-once semantics aren't enough.
+type RunRetry struct {
+	cmds        []RetryCmd
+	stopOnError bool
+	// --
+	*sync.Mutex
+	eventBus
+	running  bool
+	cmd      *cmd.Cmd
+	cur      int
+	status   []cmd.Status
+	stopChan chan struct{}
+}
+
+// NewRunRetry creates a new RunRetry for the given per-command retry
+// policies. If stopOnError is true, Run stops and returns ErrNonzeroExit
+// once a command has exhausted its retries and still exits non-zero.
+func NewRunRetry(cmds []RetryCmd, stopOnError bool) *RunRetry {
+	return &RunRetry{
+		cmds:        cmds,
+		stopOnError: stopOnError,
+		// --
+		Mutex: &sync.Mutex{},
+	}
+}
+
+// Run runs the RetryCmd list and waits for them to complete, retrying each
+// according to its policy. cmds is ignored: RunRetry runs the RetryCmd list
+// passed to NewRunRetry and implements Runner only so it can be used
+// interchangeably with other Runner implementations. Callers going through
+// the Runner interface should pass nil for cmds; passing a non-nil cmds that
+// doesn't match NewRunRetry's RetryCmd list returns ErrCmdsIgnored instead
+// of silently discarding it.
+func (r *RunRetry) Run(ctx context.Context, cmds []cmd.Cmd) ([]cmd.Status, error) {
+	if cmds != nil && !r.cmdsMatch(cmds) {
+		return nil, ErrCmdsIgnored
+	}
+
+	r.Lock()
+	if r.running {
+		r.Unlock()
+		return nil, ErrRunning
+	}
+
+	r.status = make([]cmd.Status, len(r.cmds))
+	r.stopChan = make(chan struct{})
+	r.running = true
+	r.Unlock()
+
+	defer func() {
+		r.Lock()
+		r.running = false
+		r.Unlock()
+	}()
+
+	for i, rc := range r.cmds {
+		if r.stopped() {
+			return r.status, ErrStopped
+		}
+
+		status, err := r.runOne(ctx, i, rc)
+		r.status[i] = status
+		if err != nil {
+			return r.status, err
+		}
+
+		if r.stopOnError && status.Exit != 0 {
+			return r.status, ErrNonzeroExit
+		}
+	}
+
+	return r.status, nil
+}
+
+// runOne runs a single RetryCmd, retrying on timeout up to rc.Retries times.
+// It returns early on ctx cancellation or an explicit Stop.
+func (r *RunRetry) runOne(ctx context.Context, i int, rc RetryCmd) (cmd.Status, error) {
+	var status cmd.Status
+
+	for attempt := 0; ; attempt++ {
+		child := cmd.NewCmd(rc.Cmd.Name, rc.Cmd.Args...)
+		r.Lock()
+		r.cmd = child
+		r.cur = i
+		r.Unlock()
+		r.publish(Event{Index: i, Cmd: rc.Cmd, Kind: Started})
+
+		var timeout <-chan time.Time
+		if rc.Timeout > 0 {
+			timer := time.NewTimer(rc.Timeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		var timedOut bool
+		select {
+		case status = <-child.Start():
+		case <-ctx.Done():
+			child.Stop()
+			r.clearCur()
+			r.publish(Event{Index: i, Cmd: rc.Cmd, Kind: Stopped, Status: child.Status()})
+			return status, ctx.Err()
+		case <-r.stopChan:
+			child.Stop()
+			r.clearCur()
+			r.publish(Event{Index: i, Cmd: rc.Cmd, Kind: Stopped, Status: child.Status()})
+			return status, ErrStopped
+		case <-timeout:
+			child.Stop()
+			status = child.Status()
+			timedOut = true
+		}
+
+		r.clearCur()
+
+		if !timedOut && status.Exit == 0 {
+			r.publish(Event{Index: i, Cmd: rc.Cmd, Kind: Finished, Status: status})
+			return status, nil
+		}
+		if attempt >= rc.Retries {
+			// Failed means the command never ran at all (same predicate
+			// RunPipe uses); a command that ran and simply kept exiting
+			// non-zero or timing out is Finished, just with a bad Status.
+			kind := Finished
+			if status.PID == 0 && status.Error != nil {
+				kind = Failed
+			}
+			r.publish(Event{Index: i, Cmd: rc.Cmd, Kind: kind, Status: status})
+			return status, nil
+		}
+
+		if rc.Backoff > 0 {
+			select {
+			case <-time.After(rc.Backoff):
+			case <-ctx.Done():
+				return status, ctx.Err()
+			case <-r.stopChan:
+				return status, ErrStopped
+			}
+		}
+	}
+}
+
+// cmdsMatch reports whether cmds is exactly the Cmd of each RetryCmd passed
+// to NewRunRetry, in order.
+func (r *RunRetry) cmdsMatch(cmds []cmd.Cmd) bool {
+	if len(cmds) != len(r.cmds) {
+		return false
+	}
+	for i, rc := range r.cmds {
+		if !reflect.DeepEqual(cmds[i], rc.Cmd) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RunRetry) clearCur() {
+	r.Lock()
+	r.cmd = nil
+	r.cur = -1
+	r.Unlock()
+}
+
+// Stop stops Run if Run is still running. Stop is idempotent.
+func (r *RunRetry) Stop() error {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.running {
+		return nil
+	}
+
+	if !r.stopped() {
+		close(r.stopChan)
+	}
+
+	var err error
+	if r.cmd != nil {
+		err = r.cmd.Stop()
+	}
+	return err
+}
+
+// Status returns the live snapshot of every command's Status, in the same
+// order as the RetryCmd list passed to NewRunRetry.
+func (r *RunRetry) Status() []cmd.Status {
+	r.Lock()
+	defer r.Unlock()
+	if r.cmd != nil {
+		r.status[r.cur] = r.cmd.Status()
+	}
+	return r.status
+}
+
+func (r *RunRetry) stopped() bool {
+	select {
+	case <-r.stopChan:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package run_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-cmd/cmd"
+	"github.com/go-cmd/run"
+)
+
+func TestRunRetryOK(t *testing.T) {
+	cmds := []run.RetryCmd{
+		{Cmd: cmd.Cmd{Name: "echo", Args: []string{"hello"}}, Retries: 2, Backoff: 10 * time.Millisecond},
+	}
+
+	r := run.NewRunRetry(cmds, true)
+	status, err := r.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 1 || status[0].Exit != 0 {
+		t.Fatalf("got status %+v, expected a single zero-exit Status", status)
+	}
+}
+
+func TestRunRetryExhausted(t *testing.T) {
+	cmds := []run.RetryCmd{
+		{Cmd: cmd.Cmd{Name: "false", Args: []string{}}, Retries: 2, Backoff: 10 * time.Millisecond},
+	}
+
+	r := run.NewRunRetry(cmds, true)
+	_, err := r.Run(context.Background(), nil)
+	if err != run.ErrNonzeroExit {
+		t.Errorf("got err %v, expected ErrNonzeroExit", err)
+	}
+}
+
+func TestRunRetryTimeout(t *testing.T) {
+	cmds := []run.RetryCmd{
+		{
+			Cmd:     cmd.Cmd{Name: "sleep", Args: []string{"5"}},
+			Timeout: 200 * time.Millisecond,
+			Retries: 1,
+			Backoff: 10 * time.Millisecond,
+		},
+	}
+
+	r := run.NewRunRetry(cmds, false)
+	start := time.Now()
+	status, err := r.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 1 {
+		t.Fatalf("expected 1 Status, got %d", len(status))
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Run took %s, expected both attempts to time out quickly", elapsed)
+	}
+}
+
+func TestRunRetryCmdsIgnored(t *testing.T) {
+	cmds := []run.RetryCmd{
+		{Cmd: cmd.Cmd{Name: "echo", Args: []string{"hello"}}, Retries: 2, Backoff: 10 * time.Millisecond},
+	}
+
+	r := run.NewRunRetry(cmds, true)
+
+	// Same length as cmds above but a different command entirely.
+	if _, err := r.Run(context.Background(), []cmd.Cmd{{Name: "false"}}); err != run.ErrCmdsIgnored {
+		t.Errorf("got err %v, expected ErrCmdsIgnored for a same-length mismatch", err)
+	}
+
+	// Different length.
+	if _, err := r.Run(context.Background(), []cmd.Cmd{{Name: "false"}, {Name: "false"}}); err != run.ErrCmdsIgnored {
+		t.Errorf("got err %v, expected ErrCmdsIgnored for a length mismatch", err)
+	}
+}
+
+func TestRunRetryContextCanceled(t *testing.T) {
+	cmds := []run.RetryCmd{
+		{Cmd: cmd.Cmd{Name: "sleep", Args: []string{"5"}}, Retries: 3},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	r := run.NewRunRetry(cmds, false)
+	_, err := r.Run(ctx, nil)
+	if err != context.DeadlineExceeded {
+		t.Errorf("got err %v, expected context.DeadlineExceeded", err)
+	}
+}
@@ -3,6 +3,7 @@
 package run
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-cmd/cmd"
@@ -17,6 +18,12 @@ var (
 
 	// ErrNonzeroExit is returned by a Runner if a Cmd returns a non-zero exit code.
 	ErrNonzeroExit = errors.New("non-zero exit")
+
+	// ErrCmdsIgnored is returned by a Runner whose commands are fixed at
+	// construction time (RunRetry) if Run is called with a non-nil cmds that
+	// doesn't match what it was constructed with. Pass nil for cmds when
+	// calling such a Runner through the Runner interface.
+	ErrCmdsIgnored = errors.New("cmds does not match the commands this Runner was constructed with")
 )
 
 // A Runner runs a list of commands. The interface is intentionally trivial
@@ -26,8 +33,25 @@ var (
 // running commands in parallel. The implementation details are hidden from and
 // irrelevant to the caller, which allows the caller to focus on running commands,
 // not how they are ran.
+//
+// Run takes a context.Context so callers can bound or cancel a run. If ctx is
+// canceled or its deadline expires while a command is running, the Runner
+// stops the active command and Run returns ctx.Err(). Passing context.Background()
+// preserves the old run-to-completion behavior.
+//
+// cmds is what most implementations run. A Runner whose commands are fixed
+// at construction time instead (RunRetry) ignores cmds and runs that fixed
+// list; pass nil in that case, since passing a non-nil, mismatched cmds to
+// one of those Runners returns ErrCmdsIgnored rather than silently
+// discarding it.
 type Runner interface {
-	Run([]cmd.Cmd) error
+	Run(ctx context.Context, cmds []cmd.Cmd) ([]cmd.Status, error)
 	Stop() error
 	Status() []cmd.Status
+
+	// Subscribe returns a channel of Event values describing each Cmd's
+	// lifecycle as Run executes it. Unsubscribe stops delivery to a channel
+	// returned by Subscribe.
+	Subscribe() <-chan Event
+	Unsubscribe(ch <-chan Event)
 }
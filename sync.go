@@ -1,6 +1,7 @@
 package run
 
 import (
+	"context"
 	"sync"
 
 	"github.com/go-cmd/cmd"
@@ -13,6 +14,7 @@ type RunSync struct {
 	stopOnError bool
 	// --
 	*sync.Mutex
+	eventBus
 	running bool
 	cmd     *cmd.Cmd // current running
 	cur     int      // in cmds if proc != nil
@@ -36,7 +38,10 @@ func NewRunSync(stopOnError bool) *RunSync {
 // as cmds, but if a Cmd is not ran its Status value is nil. Returned Status and
 // error are not mutually exclusive. Status for Cmd that ran are always returned,
 // even if an error is also returned.
-func (r *RunSync) Run(cmds []cmd.Cmd) ([]cmd.Status, error) {
+//
+// If ctx is canceled or its deadline expires while a Cmd is running, Run stops
+// that Cmd and returns ctx.Err().
+func (r *RunSync) Run(ctx context.Context, cmds []cmd.Cmd) ([]cmd.Status, error) {
 	r.Lock()
 	if r.running {
 		r.Unlock()
@@ -64,13 +69,25 @@ func (r *RunSync) Run(cmds []cmd.Cmd) ([]cmd.Status, error) {
 		r.cmd = cmd
 		r.cur = i
 		r.Unlock()
-
-		r.status[i] = <-cmd.Start()
+		r.publish(Event{Index: i, Cmd: c, Kind: Started})
+
+		select {
+		case r.status[i] = <-cmd.Start():
+		case <-ctx.Done():
+			cmd.Stop()
+			r.Lock()
+			r.cmd = nil
+			r.cur = -1
+			r.Unlock()
+			r.publish(Event{Index: i, Cmd: c, Kind: Stopped, Status: cmd.Status()})
+			return r.status, ctx.Err()
+		}
 
 		r.Lock()
 		r.cmd = nil
 		r.cur = -1
 		r.Unlock()
+		r.publish(Event{Index: i, Cmd: c, Kind: Finished, Status: r.status[i]})
 
 		if r.stopOnError && r.status[i].Exit != 0 {
 			return r.status, ErrNonzeroExit
@@ -1,6 +1,7 @@
 package run_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -23,16 +24,13 @@ func TestRunSyncOK(t *testing.T) {
 	}
 
 	r := run.NewRunSync(true)
-	err := r.Run(cmds)
+	_, err := r.Run(context.Background(), cmds)
 	if err != nil {
 		t.Fatal(err)
 	}
-	gotStatus, cur := r.Status()
-	if cur != -1 {
-		t.Errorf("got cur status %d, expected -1")
-	}
+	gotStatus := r.Status()
 	if len(gotStatus) != 2 {
-		t.Fatal("expected 2 Status, got %d", len(gotStatus))
+		t.Fatalf("expected 2 Status, got %d", len(gotStatus))
 	}
 	if gotStatus[0].PID == gotStatus[1].PID {
 		t.Error("status[0] and status[1] PIDs are equal, expected different")
@@ -84,23 +82,18 @@ func TestRunSyncStop(t *testing.T) {
 	}
 
 	var gotStatus []cmd.Status
-	var gotErr error
 	doneChan := make(chan struct{})
 	go func() {
-		gotErr = r.Run(cmds)
-		gotStatus, _ = r.Status()
+		gotStatus, _ = r.Run(context.Background(), cmds)
 		close(doneChan)
 	}()
 
 	time.Sleep(1 * time.Second)
 
 	// Test Status while running
-	curStatus, cur := r.Status()
-	if cur != 0 {
-		t.Error("got cur status %d, expected 0", cur)
-	}
+	curStatus := r.Status()
 	if len(curStatus) != 2 {
-		t.Fatal("expected 2 Status, got %d", len(gotStatus))
+		t.Fatalf("expected 2 Status, got %d", len(gotStatus))
 	}
 	expectStatus := []cmd.Status{
 		{
@@ -142,7 +135,7 @@ func TestRunSyncStop(t *testing.T) {
 
 	// 2 jobs in = 2 status out
 	if len(gotStatus) != 2 {
-		t.Fatal("expected 2 Status, got %d", len(gotStatus))
+		t.Fatalf("expected 2 Status, got %d", len(gotStatus))
 	}
 
 	expectStatus[0] = cmd.Status{
@@ -178,16 +171,13 @@ func TestRunSyncStopOnError(t *testing.T) {
 	}
 
 	r := run.NewRunSync(true)
-	err := r.Run(cmds)
+	_, err := r.Run(context.Background(), cmds)
 	if err != run.ErrNonzeroExit {
 		t.Error("got nil err, expected ErrNonzeroExit")
 	}
-	gotStatus, cur := r.Status()
-	if cur != -1 {
-		t.Errorf("got cur status %d, expected -1", cur)
-	}
+	gotStatus := r.Status()
 	if len(gotStatus) != 2 {
-		t.Fatal("expected 2 Status, got %d", len(gotStatus))
+		t.Fatalf("expected 2 Status, got %d", len(gotStatus))
 	}
 	expectStatus := []cmd.Status{
 		{
@@ -218,13 +208,13 @@ func TestRunSyncStopOnError(t *testing.T) {
 	// Same commands but stopOnError = false so failure is ignored
 	r = run.NewRunSync(false)
 
-	err = r.Run(cmds)
+	_, err = r.Run(context.Background(), cmds)
 	if err != nil {
 		t.Error(err)
 	}
-	gotStatus, _ = r.Status()
+	gotStatus = r.Status()
 	if len(gotStatus) != 2 {
-		t.Fatal("expected 2 Status, got %d", len(gotStatus))
+		t.Fatalf("expected 2 Status, got %d", len(gotStatus))
 	}
 	expectStatus = []cmd.Status{
 		{
@@ -272,18 +262,16 @@ func TestRunSyncStopped(t *testing.T) {
 	r := run.NewRunSync(false)
 
 	var gotStatus []cmd.Status
-	var gotErr error
 	doneChan := make(chan struct{})
 	go func() {
-		gotErr = r.Run(cmds)
-		gotStatus, _ = r.Status()
+		gotStatus, _ = r.Run(context.Background(), cmds)
 		close(doneChan)
 	}()
 
 	time.Sleep(1 * time.Second)
 
 	// Check that Run returns ErrRunning on 2nd+ call
-	err := r.Run(cmds)
+	_, err := r.Run(context.Background(), cmds)
 	if err != run.ErrRunning {
 		t.Error("got nil error, expected ErrRunning")
 	}
@@ -301,7 +289,7 @@ func TestRunSyncStopped(t *testing.T) {
 	}
 
 	if len(gotStatus) != 2 {
-		t.Fatal("expected 2 Status, got %d", len(gotStatus))
+		t.Fatalf("expected 2 Status, got %d", len(gotStatus))
 	}
 	expectStatus := []cmd.Status{
 		{